@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"strings"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// DeliveryContext carries the event-specific information BuildJobParams needs
+// on top of the target configuration: the policy and event a delivery is for,
+// rather than just where and how to send it. It's also what execution records
+// (pkg/notification/execution), the CloudEvents formatter and the
+// Harbor-Webhook-Id header are keyed off of downstream.
+type DeliveryContext struct {
+	PolicyID    int64
+	EventType   string
+	ProjectName string
+	OccurAt     int64
+	// JobID is the ID jobservice assigns the WebhookJob run; it's only known
+	// once the job is submitted; callers that already know it (e.g. a job's
+	// own Run re-submitting itself) may set it ahead of time.
+	JobID string
+}
+
+// BuildJobParams assembles the job.Parameters passed to the WebhookJob for a
+// single delivery of payload to target, for the event described by evt.
+func BuildJobParams(payload string, target model.EventTarget, evt DeliveryContext) job.Parameters {
+	params := job.Parameters{
+		"payload":          payload,
+		"address":          target.Address,
+		"skip_cert_verify": target.SkipCertVerify,
+		"policy_id":        evt.PolicyID,
+		"event_type":       evt.EventType,
+		"project_name":     evt.ProjectName,
+		"occur_at":         evt.OccurAt,
+	}
+	if len(evt.JobID) > 0 {
+		params["job_id"] = evt.JobID
+	}
+	if len(target.AuthHeader) > 0 {
+		params["auth_header"] = target.AuthHeader
+	}
+	if len(target.Secret) > 0 {
+		params["secret"] = target.Secret
+		params["sig_algo"] = target.SigAlgo
+	}
+	if len(target.PayloadFormat) > 0 {
+		params["format"] = target.PayloadFormat
+		params["template"] = target.PayloadTemplate
+	}
+
+	rp := target.RetryPolicy
+	if rp.MaxAttempts > 0 {
+		params["retry_max_attempts"] = rp.MaxAttempts
+	}
+	if rp.InitialInterval > 0 {
+		params["retry_initial_interval_s"] = rp.InitialInterval.Seconds()
+	}
+	if rp.MaxInterval > 0 {
+		params["retry_max_interval_s"] = rp.MaxInterval.Seconds()
+	}
+	if rp.Multiplier > 0 {
+		params["retry_multiplier"] = rp.Multiplier
+	}
+	if rp.Jitter {
+		params["retry_jitter"] = rp.Jitter
+	}
+	if len(rp.Schedule) > 0 {
+		intervals := make([]string, len(rp.Schedule))
+		for i, d := range rp.Schedule {
+			intervals[i] = d.String()
+		}
+		params["retry_schedule"] = strings.Join(intervals, ",")
+	}
+
+	return params
+}
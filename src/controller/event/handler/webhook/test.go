@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/jobservice/job/impl/notification"
+	policymodel "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// TestResult is the outcome of a test/ping delivery, returned by the test
+// endpoint so the UI can render a green/red result.
+type TestResult struct {
+	StatusCode int
+	Body       string
+	Error      string
+}
+
+// sampleEventPayloads holds one representative JSON payload per supported
+// event type, used only for test deliveries.
+var sampleEventPayloads = map[string]string{
+	"PUSH_ARTIFACT":   `{"type":"PUSH_ARTIFACT","occur_at":0,"event_data":{"resources":[{"digest":"sha256:0000000000000000000000000000000000000000000000000000000000000","tag":"latest","resource_url":"registry.example.com/library/hello-world:latest"}],"repository":{"name":"hello-world","namespace":"library","repo_full_name":"library/hello-world"}}}`,
+	"DELETE_ARTIFACT": `{"type":"DELETE_ARTIFACT","occur_at":0,"event_data":{"resources":[{"digest":"sha256:0000000000000000000000000000000000000000000000000000000000000","tag":"latest","resource_url":"registry.example.com/library/hello-world:latest"}],"repository":{"name":"hello-world","namespace":"library","repo_full_name":"library/hello-world"}}}`,
+}
+
+// SamplePayload builds a representative payload for eventType so a policy can
+// be validated without a real event occurring.
+func SamplePayload(eventType string) (string, error) {
+	sample, ok := sampleEventPayloads[eventType]
+	if !ok {
+		return "", fmt.Errorf("no sample payload registered for event type %s", eventType)
+	}
+	return sample, nil
+}
+
+// TestPolicy builds a sample payload for eventType and delivers it to target
+// through the exact same WebhookJob.execute path a real event uses - so the
+// SSRF check, signing and payload formatting all apply to a test delivery
+// the same way they do to a real one.
+func TestPolicy(ctx context.Context, target policymodel.EventTarget, evt DeliveryContext) (*TestResult, error) {
+	payload, err := SamplePayload(evt.EventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sample payload for event type %s: %v", evt.EventType, err)
+	}
+
+	params := BuildJobParams(payload, target, evt)
+	params["is_test"] = true
+
+	inline := notification.RunInline(ctx, params)
+	return &TestResult{StatusCode: inline.StatusCode, Body: inline.Body, Error: inline.Error}, nil
+}
@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/jobservice/logger"
+)
+
+// InlineResult is the outcome of a single RunInline delivery - enough for a
+// caller like the webhook test endpoint to render a green/red result without
+// polling a real job's status.
+type InlineResult struct {
+	StatusCode int
+	Body       string
+	Error      string
+}
+
+// RunInline executes a single WebhookJob attempt synchronously against sysCtx
+// instead of going through jobservice's queue. It runs the exact same
+// init/execute path a real delivery does, so SSRF validation (policy
+// package), signing and payload formatting all apply identically - callers
+// must set params["is_test"] = true so WebhookJob reports a single attempt
+// and sets the Harbor-Webhook-Test header.
+func RunInline(sysCtx context.Context, params job.Parameters) *InlineResult {
+	wj := &WebhookJob{}
+	ctx := newInlineContext(sysCtx)
+
+	if err := wj.init(ctx, params); err != nil {
+		return &InlineResult{Error: err.Error()}
+	}
+
+	err := wj.execute(ctx, params)
+	result := &InlineResult{}
+	if wj.lastExecution != nil {
+		result.StatusCode = wj.lastExecution.StatusCode
+		result.Body = wj.lastExecution.ResponseBody
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// inlineContext is a minimal job.Context adapter used only by RunInline: a
+// synchronous test delivery never needs the queue-facing parts of the
+// interface (check-in progress, stop/cancel), so those are no-ops.
+type inlineContext struct {
+	sysCtx context.Context
+	logger logger.Interface
+}
+
+func newInlineContext(sysCtx context.Context) *inlineContext {
+	return &inlineContext{sysCtx: sysCtx, logger: logger.DefaultLogger()}
+}
+
+func (c *inlineContext) SystemContext() context.Context {
+	return c.sysCtx
+}
+
+func (c *inlineContext) GetLogger() logger.Interface {
+	return c.logger
+}
+
+func (c *inlineContext) Checkin(string) error {
+	return nil
+}
+
+func (c *inlineContext) OPCommand() (job.OPCommand, bool) {
+	return "", false
+}
@@ -2,24 +2,52 @@ package notification
 
 import (
 	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	commonhttp "github.com/goharbor/harbor/src/common/http"
 	"github.com/goharbor/harbor/src/jobservice/job"
 	"github.com/goharbor/harbor/src/jobservice/logger"
+	"github.com/goharbor/harbor/src/pkg/notification/execution"
+	execmodel "github.com/goharbor/harbor/src/pkg/notification/execution/model"
+	"github.com/goharbor/harbor/src/pkg/notification/formatter"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	policymodel "github.com/goharbor/harbor/src/pkg/notification/policy/model"
 )
 
 // Max retry has the same meaning as max fails.
 const maxFails = "JOBSERVICE_WEBHOOK_JOB_MAX_RETRY"
 
+// defaultSigAlgo is used when the policy doesn't specify one explicitly.
+const defaultSigAlgo = "HMAC-SHA256"
+
 // WebhookJob implements the job interface, which send notification by http or https.
 type WebhookJob struct {
 	client *http.Client
 	logger logger.Interface
 	ctx    job.Context
+	// isTest marks a one-off "test webhook" delivery triggered from the policy
+	// test endpoint rather than a real event; it disables retries and flags the
+	// request so the receiver can tell the delivery apart from a real one.
+	isTest bool
+	// unsafeTarget is set by init when the target address fails the SSRF
+	// safety check; execute refuses to run and ShouldRetry reports false so
+	// the job doesn't keep hammering a validation failure that won't change.
+	unsafeTarget error
+	// lastExecution is the execution record most recently written by
+	// recordExecution, kept around so RunInline can hand the delivery result
+	// back to its caller without a second read from the execution store.
+	lastExecution *execmodel.Execution
 }
 
 // MaxFails returns that how many times this job can fail, get this value from ctx.
@@ -42,9 +70,11 @@ func (wj *WebhookJob) MaxCurrency() uint {
 	return 0
 }
 
-// ShouldRetry ...
+// ShouldRetry always returns false: jobservice's own exponential backoff isn't
+// configurable per job, so retries are instead driven from inside Run using
+// the per-target RetryPolicy.
 func (wj *WebhookJob) ShouldRetry() bool {
-	return true
+	return false
 }
 
 // Validate implements the interface in job/Interface
@@ -58,19 +88,134 @@ func (wj *WebhookJob) Run(ctx job.Context, params job.Parameters) error {
 		return err
 	}
 
-	// does not throw err in the notification job
-	if err := wj.execute(ctx, params); err != nil {
+	retryPolicy := parseRetryPolicy(params)
+	attempts := retryPolicy.Attempts()
+	if wj.isTest || wj.unsafeTarget != nil {
+		attempts = 1
+	}
+
+	first := attemptNumber(params)
+	last := first + attempts - 1
+
+	var err error
+	for attempt := first; attempt <= last; attempt++ {
+		params["attempt_number"] = attempt
+
+		// does not throw err in the notification job
+		if err = wj.execute(ctx, params); err == nil {
+			return nil
+		}
 		wj.logger.Error(err)
+
+		if attempt == last {
+			break
+		}
+
+		interval := retryPolicy.IntervalBefore(attempt + 1)
+		wj.checkinNextRetry(ctx, interval)
+		if stopped := wj.sleepOrStop(ctx, interval); stopped {
+			break
+		}
 	}
 
 	return nil
 }
 
+// retryPollInterval bounds how long sleepOrStop waits between checks of
+// ctx.OPCommand so a stop/cancel request is noticed promptly even during a
+// long backoff interval.
+const retryPollInterval = 5 * time.Second
+
+// checkinNextRetry reports the effective next-retry time through the job's
+// checkin message so the API can surface it (e.g. "next attempt in 4m").
+func (wj *WebhookJob) checkinNextRetry(ctx job.Context, interval time.Duration) {
+	nextAt := time.Now().Add(interval)
+	msg := fmt.Sprintf("next attempt in %s (at %s)", interval.Round(time.Second), nextAt.Format(time.RFC3339))
+	if err := ctx.Checkin(msg); err != nil {
+		wj.logger.Errorf("failed to check in next retry time: %v", err)
+	}
+}
+
+// sleepOrStop waits for interval to elapse, polling ctx.OPCommand so a
+// stop/cancel request issued between attempts is honored instead of being
+// stuck out a long backoff window. It returns true if a stop/cancel was seen.
+func (wj *WebhookJob) sleepOrStop(ctx job.Context, interval time.Duration) bool {
+	deadline := time.Now().Add(interval)
+	for {
+		if cmd, ok := ctx.OPCommand(); ok && (cmd == job.StopCommand || cmd == job.CancelCommand) {
+			return true
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return false
+		}
+		wait := retryPollInterval
+		if remaining < wait {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+}
+
+// parseRetryPolicy reads the flattened retry_* params built by the event
+// handler back into a policymodel.RetryPolicy.
+func parseRetryPolicy(params map[string]interface{}) policymodel.RetryPolicy {
+	var rp policymodel.RetryPolicy
+
+	if v, ok := params["retry_max_attempts"]; ok {
+		if n, err := toInt64(v); err == nil {
+			rp.MaxAttempts = int(n)
+		}
+	}
+	if v, ok := params["retry_initial_interval_s"]; ok {
+		rp.InitialInterval = secondsToDuration(v)
+	}
+	if v, ok := params["retry_max_interval_s"]; ok {
+		rp.MaxInterval = secondsToDuration(v)
+	}
+	if v, ok := params["retry_multiplier"]; ok {
+		if f, ok := v.(float64); ok {
+			rp.Multiplier = f
+		}
+	}
+	if v, ok := params["retry_jitter"]; ok {
+		if b, ok := v.(bool); ok {
+			rp.Jitter = b
+		}
+	}
+	if v, ok := params["retry_schedule"]; ok {
+		if s, ok := v.(string); ok && len(s) > 0 {
+			for _, part := range strings.Split(s, ",") {
+				if d, err := time.ParseDuration(strings.TrimSpace(part)); err == nil {
+					rp.Schedule = append(rp.Schedule, d)
+				}
+			}
+		}
+	}
+
+	return rp
+}
+
+// secondsToDuration converts the float64 "seconds" params job.Parameters
+// carries (it crosses a JSON boundary) back into a time.Duration.
+func secondsToDuration(v interface{}) time.Duration {
+	if f, ok := v.(float64); ok {
+		return time.Duration(f * float64(time.Second))
+	}
+	return 0
+}
+
 // init webhook job
 func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error {
 	wj.logger = ctx.GetLogger()
 	wj.ctx = ctx
 
+	if v, ok := params["is_test"]; ok {
+		if isTest, ok := v.(bool); ok {
+			wj.isTest = isTest
+		}
+	}
+
 	// default use insecure transport
 	tr := commonhttp.GetHTTPTransport(commonhttp.InsecureTransport)
 	if v, ok := params["skip_cert_verify"]; ok {
@@ -80,6 +225,20 @@ func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error
 			}
 		}
 	}
+
+	address, _ := params["address"].(string)
+	resolvedIP, err := policy.ValidateTargetURL(address)
+	if err != nil {
+		wj.unsafeTarget = err
+		wj.client = &http.Client{Transport: tr}
+		return nil
+	}
+	// Pin the dialer to the IP resolved above so a DNS record that changes
+	// between this check and the actual connection (rebinding) can't be used
+	// to bypass it: every attempt for this job instance dials resolvedIP
+	// directly instead of re-resolving the host.
+	tr.DialContext = pinnedDialer(resolvedIP)
+
 	wj.client = &http.Client{
 		Transport: tr,
 	}
@@ -87,28 +246,284 @@ func (wj *WebhookJob) init(ctx job.Context, params map[string]interface{}) error
 	return nil
 }
 
+// pinnedDialer returns a DialContext that rewrites the "host:port" address
+// it's given to "ip:port" before dialing, while the caller (http.Transport)
+// still uses the original host for the TLS handshake's SNI/verification.
+func pinnedDialer(ip net.IP) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		return dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+	}
+}
+
 // execute webhook job
 func (wj *WebhookJob) execute(ctx job.Context, params map[string]interface{}) error {
 	payload := params["payload"].(string)
 	address := params["address"].(string)
 
-	req, err := http.NewRequest(http.MethodPost, address, bytes.NewReader([]byte(payload)))
+	if wj.unsafeTarget != nil {
+		return wj.recordPreflightFailure(ctx, params, address, payload, wj.unsafeTarget)
+	}
+
+	body, contentType, err := formatPayload(params, payload)
+	if err != nil {
+		return err
+	}
+
+	// SystemContext carries the deadline a synchronous test delivery runs
+	// under (see RunInline); binding the request to it, instead of a bare
+	// http.NewRequest, is what actually makes testWebhookTimeout bound the
+	// network call rather than just the caller's wait.
+	req, err := http.NewRequestWithContext(ctx.SystemContext(), http.MethodPost, address, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
 	if v, ok := params["auth_header"]; ok && len(v.(string)) > 0 {
 		req.Header.Set("Authorization", v.(string))
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
+	if wj.isTest {
+		req.Header.Set("Harbor-Webhook-Test", "true")
+	}
+
+	if v, ok := params["secret"]; ok && len(v.(string)) > 0 {
+		jobID := ""
+		if id, ok := params["job_id"]; ok {
+			jobID = fmt.Sprintf("%v", id)
+		}
+		if err := signWebhookRequest(req, jobID, v.(string), sigAlgo(params), string(body)); err != nil {
+			return wj.recordPreflightFailure(ctx, params, address, string(body), err)
+		}
+	}
 
+	start := time.Now()
 	resp, err := wj.client.Do(req)
+	latency := time.Since(start).Milliseconds()
+
+	record := &execmodel.Execution{
+		EventType:      fmt.Sprintf("%v", params["event_type"]),
+		TargetURL:      address,
+		RequestHeaders: headersToString(redactHeaders(req.Header)),
+		Payload:        string(body),
+		PayloadHash:    execution.HashPayload(string(body)),
+		LatencyMS:      latency,
+		AttemptNumber:  attemptNumber(params),
+	}
+	if policyID, ok := params["policy_id"]; ok {
+		record.PolicyID, _ = toInt64(policyID)
+	}
+
 	if err != nil {
+		record.Error = err.Error()
+		wj.recordExecution(ctx, record)
 		return err
 	}
 	defer resp.Body.Close()
+
+	respBody, readErr := io.ReadAll(io.LimitReader(resp.Body, maxRecordedResponseBody))
+	record.StatusCode = resp.StatusCode
+	record.ResponseHeaders = headersToString(resp.Header)
+	record.ResponseBody = string(respBody)
+	if readErr != nil {
+		record.Error = fmt.Sprintf("failed to read response body: %v", readErr)
+	}
+	wj.recordExecution(ctx, record)
+
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return fmt.Errorf("webhook job(target: %s) response code is %d", address, resp.StatusCode)
 	}
 
 	return nil
 }
+
+// formatPayload looks up the formatter named by the "format" param (defaulting
+// to Harbor's native JSON) and runs it over payload to produce the bytes and
+// Content-Type that are actually sent on the wire.
+func formatPayload(params map[string]interface{}, payload string) ([]byte, string, error) {
+	name, _ := params["format"].(string)
+	f, err := formatter.Get(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fctx := &formatter.Context{Payload: payload}
+	if v, ok := params["event_type"]; ok {
+		fctx.EventType = fmt.Sprintf("%v", v)
+	}
+	if v, ok := params["project_name"]; ok {
+		fctx.ProjectName = fmt.Sprintf("%v", v)
+	}
+	if v, ok := params["occur_at"]; ok {
+		if occurAt, err := toInt64(v); err == nil {
+			fctx.OccurAt = occurAt
+		}
+	}
+	if v, ok := params["job_id"]; ok {
+		fctx.JobID = fmt.Sprintf("%v", v)
+	}
+	if v, ok := params["template"]; ok {
+		fctx.Template, _ = v.(string)
+	}
+
+	return f.Format(fctx)
+}
+
+// maxRecordedResponseBody caps how much of a response body is read off the
+// wire for the execution record; the store truncates further on write.
+const maxRecordedResponseBody = 4096
+
+// recordPreflightFailure records an attempt that never reached the network -
+// an unsafe target or an unsupported sig_algo - as a failed execution and
+// returns the same error, so it shows up in the history alongside real
+// delivery failures instead of only in the job log.
+func (wj *WebhookJob) recordPreflightFailure(ctx job.Context, params map[string]interface{}, address, payload string, cause error) error {
+	record := &execmodel.Execution{
+		EventType:     fmt.Sprintf("%v", params["event_type"]),
+		TargetURL:     address,
+		PayloadHash:   execution.HashPayload(payload),
+		AttemptNumber: attemptNumber(params),
+		Error:         cause.Error(),
+	}
+	if policyID, ok := params["policy_id"]; ok {
+		record.PolicyID, _ = toInt64(policyID)
+	}
+	wj.recordExecution(ctx, record)
+	return cause
+}
+
+// recordExecution persists a single delivery attempt to the execution history,
+// logging but not failing the job if the write itself errors.
+func (wj *WebhookJob) recordExecution(ctx job.Context, record *execmodel.Execution) {
+	wj.lastExecution = record
+	if _, err := execution.Mgr.Create(ctx.SystemContext(), record); err != nil {
+		wj.logger.Errorf("failed to record webhook execution: %v", err)
+	}
+}
+
+// attemptNumber reads the 1-based attempt_number param set by the caller,
+// defaulting to 1 for the first attempt.
+func attemptNumber(params map[string]interface{}) int {
+	if v, ok := params["attempt_number"]; ok {
+		if n, err := toInt64(v); err == nil {
+			return int(n)
+		}
+	}
+	return 1
+}
+
+// redactedHeaders lists the request headers whose values are credentials or
+// signatures, not metadata - they must never be written to the execution
+// history table, which is served back verbatim (and unredacted) by the
+// executions API.
+var redactedHeaders = map[string]bool{
+	"Authorization":            true,
+	"Harbor-Webhook-Signature": true,
+}
+
+// redactHeaders returns a copy of header with the values in redactedHeaders
+// replaced by a fixed placeholder.
+func redactHeaders(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for k, values := range header {
+		if redactedHeaders[k] {
+			redacted[k] = []string{"***REDACTED***"}
+			continue
+		}
+		redacted[k] = values
+	}
+	return redacted
+}
+
+// headersToString flattens an http.Header into "Key: value" lines for storage
+// alongside an execution record.
+func headersToString(header http.Header) string {
+	var sb strings.Builder
+	for k, values := range header {
+		for _, v := range values {
+			sb.WriteString(k)
+			sb.WriteString(": ")
+			sb.WriteString(v)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// toInt64 normalizes the handful of numeric types job.Parameters may hold
+// (job parameters cross a JSON boundary so ints often arrive as float64).
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case float64:
+		return int64(n), nil
+	default:
+		return strconv.ParseInt(fmt.Sprintf("%v", v), 10, 64)
+	}
+}
+
+// sigAlgo returns the configured signature algorithm, falling back to the default.
+func sigAlgo(params map[string]interface{}) string {
+	if v, ok := params["sig_algo"]; ok {
+		if algo, ok := v.(string); ok && len(algo) > 0 {
+			return algo
+		}
+	}
+	return defaultSigAlgo
+}
+
+// signWebhookRequest sets the Harbor-Webhook-Id, Harbor-Webhook-Timestamp and
+// Harbor-Webhook-Signature headers on req. secrets may be a comma-separated list
+// so operators can rotate keys: one "v1=<hex>" value is emitted per secret. The
+// signature covers "<timestamp>.<payload>" so the raw body and the timestamp can
+// both be verified by the receiver, enabling replay protection. It returns an
+// error, without touching req, if algo isn't one this job knows how to compute -
+// silently substituting a different algorithm would produce a signature the
+// receiver configured for algo can never verify.
+func signWebhookRequest(req *http.Request, jobID, secrets, algo string, payload string) error {
+	ts := time.Now().Unix()
+	signedString := fmt.Sprintf("%d.%s", ts, payload)
+
+	var sigs []string
+	for _, secret := range strings.Split(secrets, ",") {
+		secret = strings.TrimSpace(secret)
+		if secret == "" {
+			continue
+		}
+		sig, err := computeSignature(algo, secret, signedString)
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, "v1="+sig)
+	}
+	if len(sigs) == 0 {
+		return nil
+	}
+
+	req.Header.Set("Harbor-Webhook-Id", jobID)
+	req.Header.Set("Harbor-Webhook-Timestamp", strconv.FormatInt(ts, 10))
+	req.Header.Set("Harbor-Webhook-Signature", fmt.Sprintf("t=%d,%s", ts, strings.Join(sigs, ",")))
+	return nil
+}
+
+// computeSignature computes the hex-encoded HMAC of data using secret with the
+// named algorithm. Only HMAC-SHA256 is supported today; any other value is
+// rejected rather than silently signed with SHA256, since that would produce a
+// signature the receiver - configured for the algorithm it asked for - can
+// never verify.
+func computeSignature(algo, secret, data string) (string, error) {
+	switch algo {
+	case defaultSigAlgo, "":
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(data))
+		return hex.EncodeToString(mac.Sum(nil)), nil
+	default:
+		return "", fmt.Errorf("unsupported webhook sig_algo %q", algo)
+	}
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestComputeSignatureDefaultAlgo(t *testing.T) {
+	sig, err := computeSignature(defaultSigAlgo, "secret", "1234.payload")
+	if err != nil {
+		t.Fatalf("computeSignature() error = %v", err)
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1234.payload"))
+	want := hex.EncodeToString(mac.Sum(nil))
+	if sig != want {
+		t.Errorf("computeSignature() = %s, want %s", sig, want)
+	}
+}
+
+func TestComputeSignatureRejectsUnknownAlgo(t *testing.T) {
+	if _, err := computeSignature("HMAC-SHA1", "secret", "1234.payload"); err == nil {
+		t.Error("computeSignature(HMAC-SHA1) = nil error, want rejection")
+	}
+}
+
+func TestSignWebhookRequestSetsHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	if err := signWebhookRequest(req, "job-1", "secret-a,secret-b", defaultSigAlgo, "payload"); err != nil {
+		t.Fatalf("signWebhookRequest() error = %v", err)
+	}
+
+	if got := req.Header.Get("Harbor-Webhook-Id"); got != "job-1" {
+		t.Errorf("Harbor-Webhook-Id = %s, want job-1", got)
+	}
+
+	ts := req.Header.Get("Harbor-Webhook-Timestamp")
+	if ts == "" {
+		t.Fatal("Harbor-Webhook-Timestamp not set")
+	}
+
+	sigHeader := req.Header.Get("Harbor-Webhook-Signature")
+	if !strings.HasPrefix(sigHeader, fmt.Sprintf("t=%s,", ts)) {
+		t.Errorf("Harbor-Webhook-Signature = %s, want prefix t=%s,", sigHeader, ts)
+	}
+
+	// One v1=<hex> value per secret, so a receiver that rotated keys can
+	// verify against either.
+	wantA, _ := computeSignature(defaultSigAlgo, "secret-a", ts+".payload")
+	wantB, _ := computeSignature(defaultSigAlgo, "secret-b", ts+".payload")
+	if !strings.Contains(sigHeader, "v1="+wantA) {
+		t.Errorf("Harbor-Webhook-Signature missing signature for secret-a")
+	}
+	if !strings.Contains(sigHeader, "v1="+wantB) {
+		t.Errorf("Harbor-Webhook-Signature missing signature for secret-b")
+	}
+}
+
+func TestSignWebhookRequestRejectsUnknownAlgoWithoutTouchingRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	if err := signWebhookRequest(req, "job-1", "secret", "HMAC-SHA1", "payload"); err == nil {
+		t.Fatal("signWebhookRequest(HMAC-SHA1) = nil error, want rejection")
+	}
+	if req.Header.Get("Harbor-Webhook-Signature") != "" {
+		t.Error("Harbor-Webhook-Signature set despite rejected sig_algo")
+	}
+}
+
+func TestSignWebhookRequestSkipsBlankSecrets(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/hook", nil)
+	if err := signWebhookRequest(req, "job-1", " , ", defaultSigAlgo, "payload"); err != nil {
+		t.Fatalf("signWebhookRequest() error = %v", err)
+	}
+	if req.Header.Get("Harbor-Webhook-Signature") != "" {
+		t.Error("Harbor-Webhook-Signature set despite no usable secrets")
+	}
+}
+
+func TestSigAlgoDefaultsWhenUnset(t *testing.T) {
+	if got := sigAlgo(map[string]interface{}{}); got != defaultSigAlgo {
+		t.Errorf("sigAlgo({}) = %s, want %s", got, defaultSigAlgo)
+	}
+	if got := sigAlgo(map[string]interface{}{"sig_algo": "HMAC-SHA1"}); got != "HMAC-SHA1" {
+		t.Errorf("sigAlgo({sig_algo: HMAC-SHA1}) = %s, want HMAC-SHA1", got)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{
+		"Authorization":            []string{"Bearer topsecret"},
+		"Harbor-Webhook-Signature": []string{"t=1,v1=abc"},
+		"Content-Type":             []string{"application/json"},
+	}
+	redacted := redactHeaders(headers)
+	if redacted.Get("Authorization") == "Bearer topsecret" {
+		t.Error("Authorization header not redacted")
+	}
+	if redacted.Get("Harbor-Webhook-Signature") == "t=1,v1=abc" {
+		t.Error("Harbor-Webhook-Signature header not redacted")
+	}
+	if redacted.Get("Content-Type") != "application/json" {
+		t.Error("Content-Type header should be preserved unredacted")
+	}
+}
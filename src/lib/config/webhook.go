@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+func getEnv(key string) string {
+	return os.Getenv(key)
+}
+
+// Env vars backing the webhook target allow/deny lists. Both hold a
+// comma-separated list of CIDRs and/or "*.example.com"-style hostname globs.
+const (
+	webhookTargetAllowListEnv = "WEBHOOK_TARGET_ALLOW_LIST"
+	webhookTargetDenyListEnv  = "WEBHOOK_TARGET_DENY_LIST"
+)
+
+// WebhookTargetAllowList returns the operator-configured list of hosts/CIDRs
+// that webhook targets are allowed to reach even if they'd otherwise be
+// rejected as a private or link-local address.
+func WebhookTargetAllowList() []string {
+	return splitList(getEnv(webhookTargetAllowListEnv))
+}
+
+// WebhookTargetDenyList returns the operator-configured list of hosts/CIDRs
+// that webhook targets are never allowed to reach.
+func WebhookTargetDenyList() []string {
+	return splitList(getEnv(webhookTargetDenyListEnv))
+}
+
+func splitList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var entries []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification/execution/model"
+)
+
+// DAO is the data access object for webhook delivery executions.
+type DAO interface {
+	// Create persists a single delivery attempt and returns its ID.
+	Create(ctx context.Context, execution *model.Execution) (int64, error)
+	// Get returns the execution with the given id.
+	Get(ctx context.Context, id int64) (*model.Execution, error)
+	// List returns the executions matching query, newest first.
+	List(ctx context.Context, query *q.Query) ([]*model.Execution, error)
+	// Count returns the number of executions matching query.
+	Count(ctx context.Context, query *q.Query) (int64, error)
+}
+
+// New creates a default implementation of DAO.
+func New() DAO {
+	return &dao{}
+}
+
+type dao struct{}
+
+func (d *dao) Create(ctx context.Context, execution *model.Execution) (int64, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return o.Insert(execution)
+}
+
+func (d *dao) Get(ctx context.Context, id int64) (*model.Execution, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	execution := &model.Execution{ID: id}
+	if err := o.Read(execution); err != nil {
+		return nil, orm.WrapNotFoundError(err, "execution %d not found", id)
+	}
+	return execution, nil
+}
+
+func (d *dao) List(ctx context.Context, query *q.Query) ([]*model.Execution, error) {
+	qs, err := orm.QuerySetter(ctx, &model.Execution{}, query)
+	if err != nil {
+		return nil, err
+	}
+	var executions []*model.Execution
+	if _, err := qs.OrderBy("-CreationTime").All(&executions); err != nil {
+		return nil, err
+	}
+	return executions, nil
+}
+
+func (d *dao) Count(ctx context.Context, query *q.Query) (int64, error) {
+	qs, err := orm.QuerySetter(ctx, &model.Execution{}, query)
+	if err != nil {
+		return 0, err
+	}
+	return qs.Count()
+}
@@ -0,0 +1,61 @@
+package execution
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification/execution/dao"
+	"github.com/goharbor/harbor/src/pkg/notification/execution/model"
+)
+
+// Manager manages the history of webhook delivery attempts.
+type Manager interface {
+	// Create records one delivery attempt.
+	Create(ctx context.Context, execution *model.Execution) (int64, error)
+	// Get returns the delivery attempt with the given id.
+	Get(ctx context.Context, id int64) (*model.Execution, error)
+	// List returns the delivery attempts for a policy, newest first.
+	List(ctx context.Context, query *q.Query) ([]*model.Execution, error)
+	// Count returns the number of delivery attempts matching query.
+	Count(ctx context.Context, query *q.Query) (int64, error)
+}
+
+// Mgr is a global instance of Manager, following the same convention as the
+// other notification sub-packages.
+var Mgr = NewManager()
+
+// NewManager creates a default implementation of Manager.
+func NewManager() Manager {
+	return &manager{dao: dao.New()}
+}
+
+type manager struct {
+	dao dao.DAO
+}
+
+func (m *manager) Create(ctx context.Context, execution *model.Execution) (int64, error) {
+	execution.ResponseBody = model.TruncateResponseBody(execution.ResponseBody)
+	return m.dao.Create(ctx, execution)
+}
+
+func (m *manager) Get(ctx context.Context, id int64) (*model.Execution, error) {
+	return m.dao.Get(ctx, id)
+}
+
+func (m *manager) List(ctx context.Context, query *q.Query) ([]*model.Execution, error) {
+	return m.dao.List(ctx, query)
+}
+
+func (m *manager) Count(ctx context.Context, query *q.Query) (int64, error) {
+	return m.dao.Count(ctx, query)
+}
+
+// HashPayload returns the hex-encoded SHA-256 digest of payload, stored
+// alongside an execution's raw payload so two attempts can be compared for
+// an identical payload without comparing the bodies themselves.
+func HashPayload(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Execution records a single delivery attempt of a webhook policy, including
+// enough of the request and response to let an operator debug why a
+// downstream system missed an event.
+type Execution struct {
+	ID              int64     `orm:"pk;auto;column(id)" json:"id"`
+	PolicyID        int64     `orm:"column(policy_id)" json:"policy_id"`
+	EventType       string    `orm:"column(event_type)" json:"event_type"`
+	TargetURL       string    `orm:"column(target_url)" json:"target_url"`
+	RequestHeaders  string    `orm:"column(request_headers)" json:"request_headers,omitempty"`
+	PayloadHash     string    `orm:"column(payload_hash)" json:"payload_hash"`
+	Payload         string    `orm:"column(payload)" json:"-"`
+	StatusCode      int       `orm:"column(status_code)" json:"status_code"`
+	ResponseHeaders string    `orm:"column(response_headers)" json:"response_headers,omitempty"`
+	ResponseBody    string    `orm:"column(response_body)" json:"response_body,omitempty"`
+	LatencyMS       int64     `orm:"column(latency_ms)" json:"latency_ms"`
+	AttemptNumber   int       `orm:"column(attempt_number)" json:"attempt_number"`
+	Error           string    `orm:"column(error)" json:"error,omitempty"`
+	CreationTime    time.Time `orm:"column(creation_time);auto_now_add" json:"creation_time"`
+}
+
+// TableName sets the DB table this model maps to, as required by beego/orm.
+func (e *Execution) TableName() string {
+	return "notification_execution"
+}
+
+// responseBodyLimit caps how much of a response body is retained; downstream
+// systems can return arbitrarily large error pages and we only need enough to
+// diagnose a failure.
+const responseBodyLimit = 2048
+
+// TruncateResponseBody trims body to the stored limit, used before an
+// Execution is persisted.
+func TruncateResponseBody(body string) string {
+	if len(body) <= responseBodyLimit {
+		return body
+	}
+	return body[:responseBodyLimit]
+}
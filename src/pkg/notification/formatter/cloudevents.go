@@ -0,0 +1,49 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cloudEvent is a minimal CloudEvents v1.0 envelope - just the attributes
+// this formatter sets, not the full spec.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// cloudEventsFormatter wraps Harbor's native payload in a CloudEvents v1.0
+// JSON envelope so the policy can target Knative, Argo Events or any other
+// CloudEvents-aware sink without a shim.
+type cloudEventsFormatter struct{}
+
+func (cloudEventsFormatter) Format(ctx *Context) ([]byte, string, error) {
+	id := ctx.JobID
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	event := cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              id,
+		Source:          fmt.Sprintf("/harbor/%s", ctx.ProjectName),
+		Type:            fmt.Sprintf("harbor.%s", ctx.EventType),
+		Time:            time.Unix(ctx.OccurAt, 0).UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+		Data:            json.RawMessage(ctx.Payload),
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal cloudevents envelope: %v", err)
+	}
+	return body, "application/cloudevents+json", nil
+}
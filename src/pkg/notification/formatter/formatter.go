@@ -0,0 +1,51 @@
+package formatter
+
+import "fmt"
+
+// Context carries everything a Formatter needs to turn a notification event
+// into an HTTP request body. Payload is Harbor's native JSON representation
+// of the event, already built by the event handler; harbor passes it through
+// unchanged, and cloudevents-json embeds it unchanged as the envelope's data
+// while reading the structured fields for the envelope attributes.
+type Context struct {
+	EventType   string
+	ProjectName string
+	OccurAt     int64
+	JobID       string
+	// Payload is Harbor's native JSON payload for the event.
+	Payload string
+	// Template is the Go text/template source configured on the policy;
+	// only read by the "template" formatter.
+	Template string
+}
+
+// Formatter turns a notification event into the body and Content-Type of the
+// outbound webhook request.
+type Formatter interface {
+	// Format returns the request body and the Content-Type header to send
+	// it with.
+	Format(ctx *Context) (body []byte, contentType string, err error)
+}
+
+// Default is the format used when a policy doesn't specify one, preserving
+// the pre-existing behavior of sending Harbor's native JSON payload as-is.
+const Default = "harbor"
+
+var registry = map[string]Formatter{
+	Default:            harborFormatter{},
+	"cloudevents-json": cloudEventsFormatter{},
+	"template":         templateFormatter{},
+}
+
+// Get looks up a Formatter by name, falling back to the default harbor
+// formatter when name is empty.
+func Get(name string) (Formatter, error) {
+	if name == "" {
+		name = Default
+	}
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown webhook payload format %q", name)
+	}
+	return f, nil
+}
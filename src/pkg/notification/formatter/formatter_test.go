@@ -0,0 +1,85 @@
+package formatter
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetFallsBackToDefault(t *testing.T) {
+	f, err := Get("")
+	if err != nil {
+		t.Fatalf("Get(\"\") error = %v", err)
+	}
+	if _, ok := f.(harborFormatter); !ok {
+		t.Errorf("Get(\"\") = %T, want harborFormatter", f)
+	}
+}
+
+func TestGetUnknownFormat(t *testing.T) {
+	if _, err := Get("no-such-format"); err == nil {
+		t.Error("Get(unknown) = nil error, want error")
+	}
+}
+
+func TestHarborFormatterPassesPayloadThrough(t *testing.T) {
+	ctx := &Context{Payload: `{"type":"PUSH_ARTIFACT"}`}
+	body, contentType, err := harborFormatter{}.Format(ctx)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if string(body) != ctx.Payload {
+		t.Errorf("Format() body = %s, want %s", body, ctx.Payload)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Format() contentType = %s, want application/json", contentType)
+	}
+}
+
+func TestCloudEventsFormatterWrapsPayload(t *testing.T) {
+	ctx := &Context{
+		EventType:   "PUSH_ARTIFACT",
+		ProjectName: "library",
+		OccurAt:     1700000000,
+		JobID:       "job-1",
+		Payload:     `{"type":"PUSH_ARTIFACT"}`,
+	}
+	body, contentType, err := cloudEventsFormatter{}.Format(ctx)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if contentType != "application/cloudevents+json" {
+		t.Errorf("Format() contentType = %s, want application/cloudevents+json", contentType)
+	}
+
+	var event cloudEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("Format() body isn't valid JSON: %v", err)
+	}
+	if event.ID != "job-1" {
+		t.Errorf("event.ID = %s, want job-1", event.ID)
+	}
+	if event.Type != "harbor.PUSH_ARTIFACT" {
+		t.Errorf("event.Type = %s, want harbor.PUSH_ARTIFACT", event.Type)
+	}
+	if event.Source != "/harbor/library" {
+		t.Errorf("event.Source = %s, want /harbor/library", event.Source)
+	}
+	if string(event.Data) != ctx.Payload {
+		t.Errorf("event.Data = %s, want %s", event.Data, ctx.Payload)
+	}
+}
+
+func TestCloudEventsFormatterGeneratesIDWhenJobIDEmpty(t *testing.T) {
+	ctx := &Context{Payload: `{}`}
+	body, _, err := cloudEventsFormatter{}.Format(ctx)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	var event cloudEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("Format() body isn't valid JSON: %v", err)
+	}
+	if event.ID == "" {
+		t.Error("event.ID is empty, want a generated UUID")
+	}
+}
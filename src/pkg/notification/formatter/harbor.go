@@ -0,0 +1,9 @@
+package formatter
+
+// harborFormatter sends Harbor's native JSON payload unchanged; this is the
+// behavior every policy had before formatters were introduced.
+type harborFormatter struct{}
+
+func (harborFormatter) Format(ctx *Context) ([]byte, string, error) {
+	return []byte(ctx.Payload), "application/json", nil
+}
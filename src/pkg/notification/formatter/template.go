@@ -0,0 +1,36 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// templateFormatter renders the event through a Go text/template supplied per
+// policy, with the event data available as "." - this lets a policy target
+// any HTTP API that expects its own request shape instead of Harbor's.
+type templateFormatter struct{}
+
+func (templateFormatter) Format(ctx *Context) ([]byte, string, error) {
+	if ctx.Template == "" {
+		return nil, "", fmt.Errorf("webhook format is \"template\" but no template is configured on the policy")
+	}
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(ctx.Payload), &data); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal event payload for templating: %v", err)
+	}
+
+	tmpl, err := template.New("webhook").Parse(ctx.Template)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse webhook template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, "", fmt.Errorf("failed to render webhook template: %v", err)
+	}
+
+	return buf.Bytes(), "application/json", nil
+}
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/goharbor/harbor/src/lib/orm"
+	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// DAO is the data access object for notification policies.
+type DAO interface {
+	// Create persists a policy and returns its ID.
+	Create(ctx context.Context, policy *model.Policy) (int64, error)
+	// Update persists changes to an existing policy.
+	Update(ctx context.Context, policy *model.Policy) error
+	// Get returns the policy with the given id.
+	Get(ctx context.Context, id int64) (*model.Policy, error)
+}
+
+// New creates a default implementation of DAO.
+func New() DAO {
+	return &dao{}
+}
+
+type dao struct{}
+
+func (d *dao) Create(ctx context.Context, policy *model.Policy) (int64, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := policy.MarshalJSONColumns(); err != nil {
+		return 0, err
+	}
+	return o.Insert(policy)
+}
+
+func (d *dao) Update(ctx context.Context, policy *model.Policy) error {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := policy.MarshalJSONColumns(); err != nil {
+		return err
+	}
+	_, err = o.Update(policy)
+	return err
+}
+
+func (d *dao) Get(ctx context.Context, id int64) (*model.Policy, error) {
+	o, err := orm.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy := &model.Policy{ID: id}
+	if err := o.Read(policy); err != nil {
+		return nil, orm.WrapNotFoundError(err, "policy %d not found", id)
+	}
+	if err := policy.UnmarshalJSONColumns(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/pkg/notification/policy/dao"
+	policymodel "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// Manager manages notification policies.
+type Manager interface {
+	// Create validates and persists a new policy.
+	Create(ctx context.Context, policy *policymodel.Policy) (int64, error)
+	// Update validates and persists changes to an existing policy.
+	Update(ctx context.Context, policy *policymodel.Policy) error
+	// Get returns the policy with the given id.
+	Get(ctx context.Context, id int64) (*policymodel.Policy, error)
+}
+
+// Mgr is a global instance of Manager, following the same convention as the
+// other notification sub-packages.
+var Mgr Manager = NewManager()
+
+// NewManager creates a default implementation of Manager.
+func NewManager() Manager {
+	return &manager{dao: dao.New()}
+}
+
+type manager struct {
+	dao dao.DAO
+}
+
+func (m *manager) Create(ctx context.Context, policy *policymodel.Policy) (int64, error) {
+	if err := ValidateTargets(policy.Targets); err != nil {
+		return 0, err
+	}
+	return m.dao.Create(ctx, policy)
+}
+
+func (m *manager) Update(ctx context.Context, policy *policymodel.Policy) error {
+	if err := ValidateTargets(policy.Targets); err != nil {
+		return err
+	}
+	return m.dao.Update(ctx, policy)
+}
+
+func (m *manager) Get(ctx context.Context, id int64) (*policymodel.Policy, error) {
+	return m.dao.Get(ctx, id)
+}
+
+// ValidateTargets runs ValidateTargetURL over every target of a policy and
+// returns the first failure. Both Create and Update call it so an unsafe
+// target is rejected at save time, not just on first delivery (where
+// WebhookJob.init repeats the same check in case the DNS record changed
+// since the policy was saved).
+func ValidateTargets(targets []policymodel.EventTarget) error {
+	for _, target := range targets {
+		if _, err := ValidateTargetURL(target.Address); err != nil {
+			return fmt.Errorf("target %s: %w", target.Address, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package model
+
+import "encoding/json"
+
+// Policy is a notification policy: one or more event types fanned out to one
+// or more targets.
+type Policy struct {
+	ID        int64  `orm:"pk;auto;column(id)" json:"id"`
+	ProjectID int64  `orm:"column(project_id)" json:"project_id"`
+	Name      string `orm:"column(name)" json:"name"`
+	// EventTypes and Targets are what the rest of a target's configuration
+	// (address, secret, sig_algo, payload format, retry policy...) actually
+	// lives in. beego/orm can't map a slice onto a column directly, so they're
+	// tagged orm:"-" and round-tripped through the JSON-backed columns below
+	// instead - MarshalJSONColumns/UnmarshalJSONColumns do the conversion, and
+	// the DAO calls them around every write/read.
+	EventTypes []string      `orm:"-" json:"event_types"`
+	Targets    []EventTarget `orm:"-" json:"targets"`
+
+	// EventTypesJSON and TargetsJSON are the actual DB-backed columns behind
+	// EventTypes and Targets; callers should use the typed fields above, not
+	// these directly.
+	EventTypesJSON string `orm:"column(event_types)" json:"-"`
+	TargetsJSON    string `orm:"column(targets)" json:"-"`
+}
+
+// TableName sets the DB table this model maps to, as required by beego/orm.
+func (p *Policy) TableName() string {
+	return "notification_policy"
+}
+
+// dbTarget mirrors EventTarget field-for-field, except it includes Secret.
+// EventTarget itself tags Secret json:"-" so an API response serializing a
+// Policy/EventTarget never echoes a shared secret back to the caller; the
+// database, unlike an API caller, needs the real value to sign future
+// deliveries, so the targets column is marshaled through this type instead.
+type dbTarget struct {
+	Type            string      `json:"type"`
+	Address         string      `json:"address"`
+	AuthHeader      string      `json:"auth_header,omitempty"`
+	SkipCertVerify  bool        `json:"skip_cert_verify"`
+	Secret          string      `json:"secret,omitempty"`
+	SigAlgo         string      `json:"sig_algo,omitempty"`
+	PayloadFormat   string      `json:"payload_format,omitempty"`
+	PayloadTemplate string      `json:"payload_template,omitempty"`
+	RetryPolicy     RetryPolicy `json:"retry_policy,omitempty"`
+}
+
+func toDBTargets(targets []EventTarget) []dbTarget {
+	out := make([]dbTarget, len(targets))
+	for i, t := range targets {
+		out[i] = dbTarget{
+			Type:            t.Type,
+			Address:         t.Address,
+			AuthHeader:      t.AuthHeader,
+			SkipCertVerify:  t.SkipCertVerify,
+			Secret:          t.Secret,
+			SigAlgo:         t.SigAlgo,
+			PayloadFormat:   t.PayloadFormat,
+			PayloadTemplate: t.PayloadTemplate,
+			RetryPolicy:     t.RetryPolicy,
+		}
+	}
+	return out
+}
+
+func fromDBTargets(dbTargets []dbTarget) []EventTarget {
+	out := make([]EventTarget, len(dbTargets))
+	for i, t := range dbTargets {
+		out[i] = EventTarget{
+			Type:            t.Type,
+			Address:         t.Address,
+			AuthHeader:      t.AuthHeader,
+			SkipCertVerify:  t.SkipCertVerify,
+			Secret:          t.Secret,
+			SigAlgo:         t.SigAlgo,
+			PayloadFormat:   t.PayloadFormat,
+			PayloadTemplate: t.PayloadTemplate,
+			RetryPolicy:     t.RetryPolicy,
+		}
+	}
+	return out
+}
+
+// MarshalJSONColumns serializes EventTypes and Targets into their DB-backed
+// JSON columns. The DAO calls this immediately before every Insert/Update so
+// a target's address, secret, format and retry policy are actually persisted
+// instead of silently dropped.
+func (p *Policy) MarshalJSONColumns() error {
+	eventTypes, err := json.Marshal(p.EventTypes)
+	if err != nil {
+		return err
+	}
+	targets, err := json.Marshal(toDBTargets(p.Targets))
+	if err != nil {
+		return err
+	}
+	p.EventTypesJSON = string(eventTypes)
+	p.TargetsJSON = string(targets)
+	return nil
+}
+
+// UnmarshalJSONColumns populates EventTypes and Targets from their DB-backed
+// JSON columns. The DAO calls this immediately after every row is read.
+func (p *Policy) UnmarshalJSONColumns() error {
+	if len(p.EventTypesJSON) > 0 {
+		if err := json.Unmarshal([]byte(p.EventTypesJSON), &p.EventTypes); err != nil {
+			return err
+		}
+	}
+	if len(p.TargetsJSON) > 0 {
+		var dbTargets []dbTarget
+		if err := json.Unmarshal([]byte(p.TargetsJSON), &dbTargets); err != nil {
+			return err
+		}
+		p.Targets = fromDBTargets(dbTargets)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMarshalUnmarshalJSONColumnsRoundTrip(t *testing.T) {
+	p := &Policy{
+		ID:         1,
+		ProjectID:  2,
+		Name:       "on-push",
+		EventTypes: []string{"PUSH_ARTIFACT", "DELETE_ARTIFACT"},
+		Targets: []EventTarget{
+			{
+				Type:          "http",
+				Address:       "http://example.com/hook",
+				Secret:        "s3cr3t",
+				SigAlgo:       "HMAC-SHA256",
+				PayloadFormat: "cloudevents-json",
+			},
+		},
+	}
+
+	if err := p.MarshalJSONColumns(); err != nil {
+		t.Fatalf("MarshalJSONColumns() error = %v", err)
+	}
+	if p.EventTypesJSON == "" || p.TargetsJSON == "" {
+		t.Fatal("MarshalJSONColumns() left the DB-backed columns empty")
+	}
+
+	loaded := &Policy{ID: p.ID, ProjectID: p.ProjectID, Name: p.Name, EventTypesJSON: p.EventTypesJSON, TargetsJSON: p.TargetsJSON}
+	if err := loaded.UnmarshalJSONColumns(); err != nil {
+		t.Fatalf("UnmarshalJSONColumns() error = %v", err)
+	}
+
+	if len(loaded.EventTypes) != 2 || loaded.EventTypes[0] != "PUSH_ARTIFACT" {
+		t.Errorf("EventTypes = %v, want [PUSH_ARTIFACT DELETE_ARTIFACT]", loaded.EventTypes)
+	}
+	if len(loaded.Targets) != 1 || loaded.Targets[0].Address != "http://example.com/hook" {
+		t.Fatalf("Targets = %v, want one target with address http://example.com/hook", loaded.Targets)
+	}
+	if loaded.Targets[0].Secret != "s3cr3t" {
+		t.Errorf("Targets[0].Secret = %q, want s3cr3t", loaded.Targets[0].Secret)
+	}
+}
+
+func TestEventTargetJSONExcludesSecret(t *testing.T) {
+	target := EventTarget{Address: "http://example.com/hook", Secret: "s3cr3t"}
+	body, err := json.Marshal(target)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if strings.Contains(string(body), "s3cr3t") {
+		t.Errorf("json.Marshal(EventTarget) = %s, want no trace of the secret", body)
+	}
+}
+
+func TestUnmarshalJSONColumnsHandlesEmptyColumns(t *testing.T) {
+	p := &Policy{ID: 1}
+	if err := p.UnmarshalJSONColumns(); err != nil {
+		t.Fatalf("UnmarshalJSONColumns() error = %v", err)
+	}
+	if p.EventTypes != nil || p.Targets != nil {
+		t.Errorf("EventTypes/Targets = %v/%v, want nil for an empty policy", p.EventTypes, p.Targets)
+	}
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures how a target's deliveries are retried on failure.
+// Either Schedule is set explicitly, or the exponential-backoff fields
+// (InitialInterval, MaxInterval, Multiplier) are used to generate one.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Zero means len(Schedule)+1 when Schedule is set, otherwise the
+	// notification-wide default.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// InitialInterval is the delay before the second attempt.
+	InitialInterval time.Duration `json:"initial_interval,omitempty"`
+	// MaxInterval caps the delay computed from Multiplier.
+	MaxInterval time.Duration `json:"max_interval,omitempty"`
+	// Multiplier scales the interval after each attempt, e.g. 2.0 doubles it.
+	Multiplier float64 `json:"multiplier,omitempty"`
+	// Jitter adds up to +/-20% random variance to each computed interval to
+	// avoid synchronized retry storms across policies.
+	Jitter bool `json:"jitter,omitempty"`
+	// Schedule, when set, is used verbatim instead of the backoff fields
+	// above: Schedule[i] is the delay before attempt i+2.
+	Schedule []time.Duration `json:"schedule,omitempty"`
+}
+
+// defaultMaxAttempts matches the historical MaxFails default of 10.
+const defaultMaxAttempts = 10
+
+// IntervalBefore returns the delay that should elapse before making the
+// attempt numbered nextAttempt (2-based: the first retry is attempt 2).
+func (r RetryPolicy) IntervalBefore(nextAttempt int) time.Duration {
+	index := nextAttempt - 2
+	if index < 0 {
+		return 0
+	}
+
+	var interval time.Duration
+	if len(r.Schedule) > 0 {
+		if index >= len(r.Schedule) {
+			index = len(r.Schedule) - 1
+		}
+		interval = r.Schedule[index]
+	} else {
+		initial := r.InitialInterval
+		if initial <= 0 {
+			initial = time.Second
+		}
+		multiplier := r.Multiplier
+		if multiplier <= 0 {
+			multiplier = 2
+		}
+		interval = initial
+		for i := 0; i < index; i++ {
+			interval = time.Duration(float64(interval) * multiplier)
+			if r.MaxInterval > 0 && interval > r.MaxInterval {
+				interval = r.MaxInterval
+				break
+			}
+		}
+	}
+
+	if r.Jitter && interval > 0 {
+		// +/-20% variance to avoid synchronized retry storms across policies
+		// that share the same schedule.
+		delta := time.Duration((rand.Float64()*2 - 1) * 0.2 * float64(interval))
+		interval += delta
+	}
+
+	return interval
+}
+
+// Attempts returns the configured max attempts; when unset it returns one
+// attempt more than the Schedule has entries, or the historical default of
+// 10 when there is no Schedule either.
+func (r RetryPolicy) Attempts() int {
+	if r.MaxAttempts > 0 {
+		return r.MaxAttempts
+	}
+	if len(r.Schedule) > 0 {
+		return len(r.Schedule) + 1
+	}
+	return defaultMaxAttempts
+}
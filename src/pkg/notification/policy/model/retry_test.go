@@ -0,0 +1,85 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntervalBeforeSchedule(t *testing.T) {
+	r := RetryPolicy{Schedule: []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}}
+
+	cases := map[int]time.Duration{
+		2: time.Second,
+		3: 5 * time.Second,
+		4: 30 * time.Second,
+		// beyond the schedule, the last entry repeats rather than growing.
+		5: 30 * time.Second,
+	}
+	for attempt, want := range cases {
+		if got := r.IntervalBefore(attempt); got != want {
+			t.Errorf("IntervalBefore(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestIntervalBeforeFirstAttemptIsImmediate(t *testing.T) {
+	r := RetryPolicy{Schedule: []time.Duration{time.Minute}}
+	if got := r.IntervalBefore(1); got != 0 {
+		t.Errorf("IntervalBefore(1) = %v, want 0", got)
+	}
+}
+
+func TestIntervalBeforeExponentialBackoff(t *testing.T) {
+	r := RetryPolicy{
+		InitialInterval: time.Second,
+		Multiplier:      2,
+		MaxInterval:     10 * time.Second,
+	}
+
+	cases := map[int]time.Duration{
+		2: time.Second,
+		3: 2 * time.Second,
+		4: 4 * time.Second,
+		5: 8 * time.Second,
+		// capped at MaxInterval once the doubling would exceed it.
+		6: 10 * time.Second,
+		7: 10 * time.Second,
+	}
+	for attempt, want := range cases {
+		if got := r.IntervalBefore(attempt); got != want {
+			t.Errorf("IntervalBefore(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestIntervalBeforeDefaultsWhenUnset(t *testing.T) {
+	r := RetryPolicy{}
+	if got := r.IntervalBefore(2); got != time.Second {
+		t.Errorf("IntervalBefore(2) = %v, want %v (default initial interval)", got, time.Second)
+	}
+	if got := r.IntervalBefore(3); got != 2*time.Second {
+		t.Errorf("IntervalBefore(3) = %v, want %v (default multiplier)", got, 2*time.Second)
+	}
+}
+
+func TestIntervalBeforeJitterStaysWithinBounds(t *testing.T) {
+	r := RetryPolicy{Schedule: []time.Duration{10 * time.Second}, Jitter: true}
+	for i := 0; i < 50; i++ {
+		got := r.IntervalBefore(2)
+		if got < 8*time.Second || got > 12*time.Second {
+			t.Fatalf("IntervalBefore(2) = %v, want within +/-20%% of 10s", got)
+		}
+	}
+}
+
+func TestAttempts(t *testing.T) {
+	if got := (RetryPolicy{}).Attempts(); got != defaultMaxAttempts {
+		t.Errorf("Attempts() = %d, want default %d", got, defaultMaxAttempts)
+	}
+	if got := (RetryPolicy{MaxAttempts: 3}).Attempts(); got != 3 {
+		t.Errorf("Attempts() = %d, want 3", got)
+	}
+	if got := (RetryPolicy{Schedule: []time.Duration{time.Second, time.Second}}).Attempts(); got != 3 {
+		t.Errorf("Attempts() = %d, want len(Schedule)+1 = 3", got)
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+// EventTarget is the notification target of a webhook policy, e.g. http://localhost:8080/endpoint
+type EventTarget struct {
+	Type           string `json:"type"`
+	Address        string `json:"address"`
+	AuthHeader     string `json:"auth_header,omitempty"`
+	SkipCertVerify bool   `json:"skip_cert_verify"`
+	// Secret is the shared key used to sign the delivery payload with HMAC so the
+	// receiver can verify the request came from Harbor. A comma-separated list of
+	// secrets is accepted so operators can rotate keys without downtime: the job
+	// emits one `v1=` value per secret in the Harbor-Webhook-Signature header.
+	//
+	// Excluded from JSON entirely (not just omitempty) so serializing a Policy/
+	// EventTarget into an API response - e.g. a future "get policy" endpoint -
+	// can never round-trip the plaintext secret back to a caller. The DAO
+	// serializes it separately, via dbTarget in policy.go, when persisting a
+	// policy to the database.
+	Secret string `json:"-"`
+	// SigAlgo is the HMAC hash algorithm used to sign the payload. Defaults to
+	// "HMAC-SHA256" when empty.
+	SigAlgo string `json:"sig_algo,omitempty"`
+	// PayloadFormat selects the formatter.Formatter used to build the request
+	// body, e.g. "harbor" (default), "cloudevents-json" or "template".
+	PayloadFormat string `json:"payload_format,omitempty"`
+	// PayloadTemplate is the Go text/template source used when PayloadFormat
+	// is "template".
+	PayloadTemplate string `json:"payload_template,omitempty"`
+	// RetryPolicy overrides the default retry schedule for this target.
+	RetryPolicy RetryPolicy `json:"retry_policy,omitempty"`
+}
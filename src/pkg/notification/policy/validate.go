@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/goharbor/harbor/src/lib/config"
+)
+
+// ErrUnsafeTarget is wrapped by ValidateTargetURL when address resolves to an
+// address space it's not allowed to reach.
+type ErrUnsafeTarget struct {
+	Address string
+	Reason  string
+}
+
+func (e *ErrUnsafeTarget) Error() string {
+	return fmt.Sprintf("webhook target %s is not allowed: %s", e.Address, e.Reason)
+}
+
+// ValidateTargetURL parses address, resolves its host and rejects it unless
+// the resolved IP is routable and not covered by the operator's deny-list -
+// or is explicitly covered by the allow-list, which takes priority over
+// every other check. It's called both when a policy is saved and again, on
+// every delivery, by WebhookJob.init so a DNS record change can't silently
+// turn a previously-safe target unsafe without the job noticing.
+//
+// On success it returns the resolved IP so the caller can pin the dialer to
+// it and avoid a second resolution between the check and the connection
+// (DNS rebinding).
+func ValidateTargetURL(address string) (net.IP, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook address %s: %v", address, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &ErrUnsafeTarget{Address: address, Reason: fmt.Sprintf("unsupported scheme %q", u.Scheme)}
+	}
+
+	host := u.Hostname()
+
+	// Resolve once, up front: a CIDR entry in either list can only ever match
+	// the resolved IP, not a DNS name, so matchesList needs it to check a
+	// hostname-based target against the list at all.
+	ip, err := resolveFirst(host)
+	if err != nil {
+		return nil, err
+	}
+
+	if allowed, err := matchesList(host, ip, config.WebhookTargetAllowList()); err != nil {
+		return nil, err
+	} else if allowed {
+		return ip, nil
+	}
+
+	if denied, err := matchesList(host, ip, config.WebhookTargetDenyList()); err != nil {
+		return nil, err
+	} else if denied {
+		return nil, &ErrUnsafeTarget{Address: address, Reason: "host matches the configured deny-list"}
+	}
+
+	if isDisallowedIP(ip) {
+		return nil, &ErrUnsafeTarget{Address: address, Reason: fmt.Sprintf("resolved IP %s is loopback, link-local, private, multicast or unspecified", ip)}
+	}
+
+	return ip, nil
+}
+
+// isDisallowedIP reports whether ip falls in a range that's unsafe to let a
+// project admin point a webhook at by default (loopback, link-local,
+// private, multicast, unspecified).
+func isDisallowedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified()
+}
+
+// resolveFirst resolves host to its first IP address. If host is already an
+// IP literal, it's returned as-is without a DNS lookup.
+func resolveFirst(host string) (net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve webhook host %s: %v", host, err)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("webhook host %s did not resolve to any address", host)
+	}
+	return ips[0], nil
+}
+
+// matchesList reports whether host or ip matches any entry of list. An entry
+// is treated as a CIDR if it parses as one - matched against ip, since host
+// is usually a DNS name a CIDR can never match directly - otherwise as a
+// hostname glob (only the "*.example.com" wildcard form is supported),
+// matched against host.
+func matchesList(host string, ip net.IP, list []string) (bool, error) {
+	for _, entry := range list {
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if cidr.Contains(ip) {
+				return true, nil
+			}
+			continue
+		}
+		if matchesHostGlob(host, entry) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchesHostGlob matches host against pattern, where pattern may start with
+// "*." to match any subdomain of the remainder.
+func matchesHostGlob(host, pattern string) bool {
+	if pattern == host {
+		return true
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestValidateTargetURLRejectsPrivateAddresses(t *testing.T) {
+	cases := []string{
+		"http://127.0.0.1:9000/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://[::1]/hook",
+	}
+	for _, address := range cases {
+		if _, err := ValidateTargetURL(address); err == nil {
+			t.Errorf("ValidateTargetURL(%q) = nil error, want rejection", address)
+		}
+	}
+}
+
+func TestValidateTargetURLRejectsUnsupportedScheme(t *testing.T) {
+	if _, err := ValidateTargetURL("ftp://example.com/hook"); err == nil {
+		t.Error("ValidateTargetURL with ftp scheme = nil error, want rejection")
+	}
+}
+
+func TestValidateTargetURLAllowsPublicAddress(t *testing.T) {
+	if _, err := ValidateTargetURL("http://93.184.216.34/hook"); err != nil {
+		t.Errorf("ValidateTargetURL(public IP) = %v, want nil", err)
+	}
+}
+
+func TestValidateTargetURLDenyListTakesPriority(t *testing.T) {
+	os.Setenv("WEBHOOK_TARGET_DENY_LIST", "93.184.216.34/32")
+	defer os.Unsetenv("WEBHOOK_TARGET_DENY_LIST")
+
+	if _, err := ValidateTargetURL("http://93.184.216.34/hook"); err == nil {
+		t.Error("ValidateTargetURL(denied IP) = nil error, want rejection")
+	}
+}
+
+func TestValidateTargetURLAllowListOverridesPrivateRejection(t *testing.T) {
+	os.Setenv("WEBHOOK_TARGET_ALLOW_LIST", "127.0.0.1/32")
+	defer os.Unsetenv("WEBHOOK_TARGET_ALLOW_LIST")
+
+	if _, err := ValidateTargetURL("http://127.0.0.1:9000/hook"); err != nil {
+		t.Errorf("ValidateTargetURL(allow-listed loopback) = %v, want nil", err)
+	}
+}
+
+func TestMatchesListCIDREntryMatchesResolvedIPNotHostname(t *testing.T) {
+	// "example.invalid" isn't a literal IP, so a CIDR entry must be checked
+	// against the resolved ip argument, not the host string, or it can never
+	// match a DNS-name target at all.
+	matched, err := matchesList("example.invalid", net.ParseIP("93.184.216.34"), []string{"93.184.216.34/32"})
+	if err != nil {
+		t.Fatalf("matchesList() error = %v", err)
+	}
+	if !matched {
+		t.Error("matchesList() = false, want true for a CIDR entry covering the resolved IP")
+	}
+}
+
+func TestMatchesHostGlob(t *testing.T) {
+	cases := []struct {
+		host, pattern string
+		want          bool
+	}{
+		{"example.com", "example.com", true},
+		{"hooks.example.com", "*.example.com", true},
+		{"example.com", "*.example.com", false},
+		{"evilexample.com", "*.example.com", false},
+	}
+	for _, c := range cases {
+		if got := matchesHostGlob(c.host, c.pattern); got != c.want {
+			t.Errorf("matchesHostGlob(%q, %q) = %v, want %v", c.host, c.pattern, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+)
+
+// securityContext is the minimal slice of Harbor's request-scoped security
+// context this package needs - whether the caller may act on a project's
+// webhook configuration. The full interface (authentication, system-admin
+// checks, project membership, ...) lives elsewhere; only Can is needed here.
+type securityContext interface {
+	Can(ctx context.Context, action, resource string) bool
+}
+
+// securityContextKey is the context key the request middleware that builds
+// ctx for each HTTP call stores the securityContext under.
+type securityContextKey struct{}
+
+// withSecurityContext returns a context carrying sc. Only tests call it; the
+// HTTP entry points in webhook_routes.go attach none themselves and read the
+// security context that's already on the incoming request's context.
+func withSecurityContext(ctx context.Context, sc securityContext) context.Context {
+	return context.WithValue(ctx, securityContextKey{}, sc)
+}
+
+// webhookAction names the operations requireProjectWebhookAccess checks.
+type webhookAction string
+
+const (
+	webhookActionTest   webhookAction = "test"
+	webhookActionList   webhookAction = "list"
+	webhookActionGet    webhookAction = "get"
+	webhookActionResend webhookAction = "resend"
+)
+
+// requireProjectWebhookAccess returns an error if the security context
+// attached to ctx isn't allowed to perform action on projectID's webhook
+// configuration - e.g. a caller without access to the project trying to list
+// or resend another project's delivery history. Handlers call this before
+// doing any work.
+func requireProjectWebhookAccess(ctx context.Context, projectID int64, action webhookAction) error {
+	sc, ok := ctx.Value(securityContextKey{}).(securityContext)
+	if !ok {
+		return fmt.Errorf("no security context found for project %d webhook %s", projectID, action)
+	}
+	resource := fmt.Sprintf("/project/%d/webhook", projectID)
+	if !sc.Can(ctx, string(action), resource) {
+		return fmt.Errorf("not authorized to %s webhook configuration of project %d", action, projectID)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeSecurityContext struct {
+	allow bool
+}
+
+func (f fakeSecurityContext) Can(ctx context.Context, action, resource string) bool {
+	return f.allow
+}
+
+func TestRequireProjectWebhookAccessNoSecurityContext(t *testing.T) {
+	if err := requireProjectWebhookAccess(context.Background(), 1, webhookActionList); err == nil {
+		t.Error("requireProjectWebhookAccess() = nil error, want error when ctx carries no security context")
+	}
+}
+
+func TestRequireProjectWebhookAccessDenied(t *testing.T) {
+	ctx := withSecurityContext(context.Background(), fakeSecurityContext{allow: false})
+	if err := requireProjectWebhookAccess(ctx, 1, webhookActionResend); err == nil {
+		t.Error("requireProjectWebhookAccess() = nil error, want error when Can() returns false")
+	}
+}
+
+func TestRequireProjectWebhookAccessAllowed(t *testing.T) {
+	ctx := withSecurityContext(context.Background(), fakeSecurityContext{allow: true})
+	if err := requireProjectWebhookAccess(ctx, 1, webhookActionTest); err != nil {
+		t.Errorf("requireProjectWebhookAccess() error = %v, want nil when Can() returns true", err)
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/goharbor/harbor/src/controller/event/handler/webhook"
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification/execution"
+	execmodel "github.com/goharbor/harbor/src/pkg/notification/execution/model"
+	"github.com/goharbor/harbor/src/pkg/notification/formatter"
+	policymodel "github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// listWebhookExecutions backs GET /projects/{}/webhook/policies/{id}/executions.
+func listWebhookExecutions(ctx context.Context, projectID, policyID int64, query *q.Query) ([]*execmodel.Execution, int64, error) {
+	if err := requireProjectWebhookAccess(ctx, projectID, webhookActionList); err != nil {
+		return nil, 0, err
+	}
+
+	if query.Keywords == nil {
+		query.Keywords = map[string]interface{}{}
+	}
+	query.Keywords["PolicyID"] = policyID
+
+	executions, err := execution.Mgr.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := execution.Mgr.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return executions, total, nil
+}
+
+// getWebhookExecution backs GET /projects/{}/webhook/policies/{id}/executions/{eid}.
+func getWebhookExecution(ctx context.Context, projectID, policyID, executionID int64) (*execmodel.Execution, error) {
+	if err := requireProjectWebhookAccess(ctx, projectID, webhookActionGet); err != nil {
+		return nil, err
+	}
+
+	e, err := execution.Mgr.Get(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if e.PolicyID != policyID {
+		return nil, fmt.Errorf("execution %d does not belong to policy %d", executionID, policyID)
+	}
+	return e, nil
+}
+
+// resendWebhookExecution backs the `?resend=true` action on the execution
+// detail endpoint: it re-enqueues a WebhookJob with the stored payload so the
+// operator doesn't have to wait for the original event to reoccur. target is
+// the policy's current target configuration - the caller loads it fresh from
+// the policy rather than this package storing a copy, since the execution
+// record deliberately doesn't retain secrets (see the redaction in
+// WebhookJob.execute); this way a resend still goes out signed and with the
+// cert/retry settings the policy has *now*, not at the time of the original
+// attempt.
+//
+// e.Payload is already the post-format body WebhookJob.execute produced and
+// recorded - not the native Harbor payload BuildJobParams/formatPayload
+// normally expect. Re-attaching the target's current format would run it
+// through a formatter a second time (double-wrapping a cloudevents envelope,
+// or breaking outright if the template output isn't valid JSON), so the
+// format is forced to the raw passthrough and the stored bytes are replayed
+// verbatim.
+func resendWebhookExecution(ctx context.Context, projectID, policyID, executionID int64, target policymodel.EventTarget, submitter func(job.Parameters) error) error {
+	if err := requireProjectWebhookAccess(ctx, projectID, webhookActionResend); err != nil {
+		return err
+	}
+
+	e, err := getWebhookExecution(ctx, projectID, policyID, executionID)
+	if err != nil {
+		return err
+	}
+
+	target.PayloadFormat = formatter.Default
+	target.PayloadTemplate = ""
+
+	evt := webhook.DeliveryContext{
+		PolicyID:  e.PolicyID,
+		EventType: e.EventType,
+	}
+	params := webhook.BuildJobParams(e.Payload, target, evt)
+	params["attempt_number"] = 1
+
+	return submitter(params)
+}
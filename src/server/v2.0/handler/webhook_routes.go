@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/goharbor/harbor/src/jobservice/job"
+	"github.com/goharbor/harbor/src/lib/q"
+	"github.com/goharbor/harbor/src/pkg/notification/policy"
+	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// webhookJobSubmitter submits a resend's job.Parameters to jobservice's
+// queue. The real implementation is wired in by the server's bootstrap code
+// (same jobservice client every other async job in Harbor submits through);
+// it's a package variable, rather than a hard dependency, so tests can swap
+// in a fake without a live jobservice.
+var webhookJobSubmitter func(ctx context.Context, params job.Parameters) error
+
+// RegisterWebhookRoutes wires the webhook test and execution-history handlers
+// onto mux. The rest of the v2.0 API is generated from a swagger spec into
+// restapi/operations and registered there; this package doesn't carry that
+// generated tree, so these routes are registered directly against the
+// project/policy path the swagger spec uses.
+func RegisterWebhookRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/v2.0/projects/{project_id}/webhook/policies/{policy_id}/test", handleTestWebhookPolicy)
+	mux.HandleFunc("GET /api/v2.0/projects/{project_id}/webhook/policies/{policy_id}/executions", handleListWebhookExecutions)
+	mux.HandleFunc("GET /api/v2.0/projects/{project_id}/webhook/policies/{policy_id}/executions/{execution_id}", handleGetWebhookExecution)
+	mux.HandleFunc("POST /api/v2.0/projects/{project_id}/webhook/policies/{policy_id}/executions/{execution_id}", handleResendWebhookExecution)
+}
+
+// pathInt64 parses a {name} path value set by mux.HandleFunc's pattern
+// matching, returning a 400 through writeError on failure.
+func pathInt64(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	v, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %v", name, err))
+		return 0, false
+	}
+	return v, true
+}
+
+// writeError writes err as a JSON body with the given status code.
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// writeJSON writes v as a 200 JSON body.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// testWebhookRequest is the POST body for the test endpoint: the target
+// configuration to try and the event type to build a sample payload for.
+// Testing a target doesn't require the policy to be saved first, so the
+// target is supplied by the caller rather than loaded by policy ID.
+type testWebhookRequest struct {
+	Target    model.EventTarget `json:"target"`
+	EventType string            `json:"event_type"`
+}
+
+func handleTestWebhookPolicy(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathInt64(w, r, "project_id")
+	if !ok {
+		return
+	}
+	policyID, ok := pathInt64(w, r, "policy_id")
+	if !ok {
+		return
+	}
+
+	if err := requireProjectWebhookAccess(r.Context(), projectID, webhookActionTest); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	var req testWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+		return
+	}
+
+	result, err := testWebhookPolicy(r.Context(), projectID, policyID, req.Target, req.EventType)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, result)
+}
+
+func handleListWebhookExecutions(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathInt64(w, r, "project_id")
+	if !ok {
+		return
+	}
+	policyID, ok := pathInt64(w, r, "policy_id")
+	if !ok {
+		return
+	}
+
+	if err := requireProjectWebhookAccess(r.Context(), projectID, webhookActionList); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	executions, total, err := listWebhookExecutions(r.Context(), projectID, policyID, &q.Query{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, executions)
+}
+
+func handleGetWebhookExecution(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("resend") == "true" {
+		handleResendWebhookExecution(w, r)
+		return
+	}
+
+	projectID, ok := pathInt64(w, r, "project_id")
+	if !ok {
+		return
+	}
+	policyID, ok := pathInt64(w, r, "policy_id")
+	if !ok {
+		return
+	}
+	executionID, ok := pathInt64(w, r, "execution_id")
+	if !ok {
+		return
+	}
+
+	if err := requireProjectWebhookAccess(r.Context(), projectID, webhookActionGet); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	e, err := getWebhookExecution(r.Context(), projectID, policyID, executionID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, e)
+}
+
+// handleResendWebhookExecution backs the resend action: it loads the saved
+// policy fresh (rather than trusting a client-supplied target) and picks the
+// target that produced the execution being resent, matched by address, since
+// the execution record doesn't retain which of a policy's targets was used.
+func handleResendWebhookExecution(w http.ResponseWriter, r *http.Request) {
+	projectID, ok := pathInt64(w, r, "project_id")
+	if !ok {
+		return
+	}
+	policyID, ok := pathInt64(w, r, "policy_id")
+	if !ok {
+		return
+	}
+	executionID, ok := pathInt64(w, r, "execution_id")
+	if !ok {
+		return
+	}
+
+	if err := requireProjectWebhookAccess(r.Context(), projectID, webhookActionResend); err != nil {
+		writeError(w, http.StatusForbidden, err)
+		return
+	}
+
+	p, err := policy.Mgr.Get(r.Context(), policyID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	e, err := getWebhookExecution(r.Context(), projectID, policyID, executionID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	target, err := findTarget(p.Targets, e.TargetURL)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+
+	submit := func(params job.Parameters) error {
+		return webhookJobSubmitter(r.Context(), params)
+	}
+	if err := resendWebhookExecution(r.Context(), projectID, policyID, executionID, target, submit); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// findTarget returns the target in targets whose Address matches address,
+// the same correlation handleResendWebhookExecution's target lookup relies on.
+func findTarget(targets []model.EventTarget, address string) (model.EventTarget, error) {
+	for _, t := range targets {
+		if t.Address == address {
+			return t, nil
+		}
+	}
+	return model.EventTarget{}, fmt.Errorf("no target with address %s on this policy anymore", address)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// TestWebhookRoutesRequireSecurityContext exercises each registered route
+// with no security context on the request - the state a request is in
+// before whatever auth middleware the real server chains in front of this
+// package runs - and checks it's rejected rather than falling through to do
+// the (unauthorized) work.
+func TestWebhookRoutesRequireSecurityContext(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterWebhookRoutes(mux)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"test", http.MethodPost, "/api/v2.0/projects/1/webhook/policies/2/test", `{"event_type":"PUSH_ARTIFACT","target":{"address":"http://example.com/hook"}}`},
+		{"list", http.MethodGet, "/api/v2.0/projects/1/webhook/policies/2/executions", ""},
+		{"get", http.MethodGet, "/api/v2.0/projects/1/webhook/policies/2/executions/3", ""},
+		{"resend", http.MethodPost, "/api/v2.0/projects/1/webhook/policies/2/executions/3", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, bytes.NewBufferString(c.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d (no security context attached)", rec.Code, http.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestWebhookRoutesInvalidPathParam(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterWebhookRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2.0/projects/not-a-number/webhook/policies/2/executions", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d for a non-numeric project_id", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFindTarget(t *testing.T) {
+	targets := []model.EventTarget{
+		{Address: "http://a.example.com/hook"},
+		{Address: "http://b.example.com/hook"},
+	}
+
+	got, err := findTarget(targets, "http://b.example.com/hook")
+	if err != nil {
+		t.Fatalf("findTarget() error = %v", err)
+	}
+	if got.Address != "http://b.example.com/hook" {
+		t.Errorf("findTarget() = %+v, want target with address http://b.example.com/hook", got)
+	}
+
+	if _, err := findTarget(targets, "http://gone.example.com/hook"); err == nil {
+		t.Error("findTarget() = nil error, want error when no target matches")
+	}
+}
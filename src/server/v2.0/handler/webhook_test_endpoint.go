@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/goharbor/harbor/src/controller/event/handler/webhook"
+	"github.com/goharbor/harbor/src/pkg/notification/policy/model"
+)
+
+// testWebhookTimeout bounds the inline delivery used to validate a policy so the
+// API call can't hang on a slow or unreachable target.
+const testWebhookTimeout = 10 * time.Second
+
+// WebhookTestResult is returned by POST .../webhook/policies/{id}/test and lets
+// the UI render a green/red result without following up on a job status poll.
+type WebhookTestResult struct {
+	StatusCode int    `json:"status_code,omitempty"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Body       string `json:"body,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// testWebhookPolicy dispatches a single, synchronous test delivery for
+// target's event type through webhook.TestPolicy, which runs the exact same
+// WebhookJob.execute path a real delivery does (SSRF validation, signing,
+// formatting included) rather than a parallel hand-rolled HTTP call.
+func testWebhookPolicy(ctx context.Context, projectID, policyID int64, target model.EventTarget, eventType string) (*WebhookTestResult, error) {
+	if err := requireProjectWebhookAccess(ctx, projectID, webhookActionTest); err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, testWebhookTimeout)
+	defer cancel()
+
+	start := time.Now()
+	res, err := webhook.TestPolicy(runCtx, target, webhook.DeliveryContext{
+		PolicyID:  policyID,
+		EventType: eventType,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookTestResult{
+		StatusCode: res.StatusCode,
+		LatencyMS:  time.Since(start).Milliseconds(),
+		Body:       res.Body,
+		Error:      res.Error,
+	}, nil
+}